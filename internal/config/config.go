@@ -0,0 +1,29 @@
+// Package config holds the process-wide configuration for myshoes, loaded once at startup.
+package config
+
+import "time"
+
+// Config is the process-wide configuration, populated at startup.
+var Config Configuration
+
+// Configuration is myshoes' runtime configuration.
+type Configuration struct {
+	// MaxConnectionsToBackend is the maximum number of concurrent connections the starter will
+	// open to the runner backend.
+	MaxConnectionsToBackend int
+	// MaxConcurrencyDeleting is the maximum number of runner deletions that may be in flight at
+	// once.
+	MaxConcurrencyDeleting int
+	// MaxConcurrencyPendingScan is the maximum number of repositories scanned concurrently for
+	// pending workflow runs. See datastore.GetPendingWorkflowRunByRecentRepositories.
+	MaxConcurrencyPendingScan int
+	// DefaultJobRetention is how long a completed job's result is kept before the retention
+	// sweeper deletes it, for targets that don't configure their own retention.
+	DefaultJobRetention time.Duration
+	// PendingPolicy overrides how pending workflow run detection decides whether a target should
+	// be scanned and how long a run must wait before it counts as pending. It is typed as
+	// interface{} (rather than datastore.PendingPolicy) to avoid internal/config importing
+	// pkg/datastore, which itself imports internal/config; callers type-assert it back to
+	// datastore.PendingPolicy. Left nil, callers fall back to their own default policy.
+	PendingPolicy interface{}
+}
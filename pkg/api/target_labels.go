@@ -0,0 +1,72 @@
+// Package api exposes CRUD HTTP endpoints for operator-facing target configuration.
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/pkg/datastore"
+)
+
+// TargetLabelsHandler reads and replaces the labels advertised by a target, e.g. for an
+// operator to register "arch=amd64,gpu=*" on a GPU pool. A target is addressed by the
+// "target_id" query parameter rather than a path segment, since the net/http version this
+// module targets predates method-aware path patterns.
+type TargetLabelsHandler struct {
+	DS datastore.Datastore
+}
+
+// ServeHTTP implements http.Handler. GET returns the target's current labels as a JSON object;
+// PUT replaces them wholesale with the JSON object in the request body.
+func (h *TargetLabelsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	targetID, err := uuid.FromString(r.URL.Query().Get("target_id"))
+	if err != nil {
+		http.Error(w, "invalid target_id", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.get(w, r, targetID)
+	case http.MethodPut:
+		h.put(w, r, targetID)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *TargetLabelsHandler) get(w http.ResponseWriter, r *http.Request, targetID uuid.UUID) {
+	target, err := h.DS.GetTarget(r.Context(), targetID)
+	if err != nil {
+		if err == datastore.ErrNotFound {
+			http.Error(w, "target not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "failed to get target", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(target.Labels)
+}
+
+func (h *TargetLabelsHandler) put(w http.ResponseWriter, r *http.Request, targetID uuid.UUID) {
+	var labels datastore.TargetLabels
+	if err := json.NewDecoder(r.Body).Decode(&labels); err != nil {
+		http.Error(w, "invalid labels payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.DS.UpdateTargetLabels(r.Context(), targetID, labels); err != nil {
+		if err == datastore.ErrNotFound {
+			http.Error(w, "target not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "failed to update target labels", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
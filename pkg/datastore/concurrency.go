@@ -0,0 +1,59 @@
+package datastore
+
+import (
+	"context"
+	"sync"
+)
+
+// ForEachJob runs fn for each index in [0, length) using a bounded number of
+// concurrent goroutines. Once ctx is cancelled no further indexes are started,
+// but ForEachJob still blocks until every call to fn already started has
+// returned. If concurrency is less than 1, a concurrency of 1 is used.
+//
+// fn is responsible for handling its own errors (e.g. logging and
+// continuing); an error returned from fn does not stop processing of other
+// indexes, but does cause ForEachJob to return that error once all launched
+// work has finished. If ctx is cancelled before an index is started, that
+// index is skipped.
+func ForEachJob(ctx context.Context, length, concurrency int, fn func(ctx context.Context, i int) error) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	var mu sync.Mutex
+	var firstErr error
+
+	for i := 0; i < length; i++ {
+		if ctx.Err() != nil {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+		case sem <- struct{}{}:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := fn(ctx, i); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	return firstErr
+}
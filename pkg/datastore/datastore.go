@@ -0,0 +1,144 @@
+package datastore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// ErrNotFound is returned when a lookup does not match any row
+var ErrNotFound = errors.New("not found")
+
+// IsNotLocked / IsLocked describe the result of IsLocked
+const (
+	IsNotLocked = "not locked"
+	IsLocked    = "locked"
+)
+
+// ResourceType is the resource class a target's runners are created with (instance size, etc.)
+type ResourceType string
+
+// TargetStatus is the lifecycle status of a Target
+type TargetStatus string
+
+// TargetStatus values
+const (
+	TargetStatusInitialize TargetStatus = "initialize"
+	TargetStatusActive     TargetStatus = "active"
+	TargetStatusErr        TargetStatus = "err"
+	TargetStatusDeleted    TargetStatus = "deleted"
+)
+
+// RunnerStatus is the lifecycle status of a Runner, also used as the reason a Runner was deleted
+type RunnerStatus string
+
+// RunnerStatus values
+const (
+	RunnerStatusInitialize RunnerStatus = "initialize"
+	RunnerStatusRunning    RunnerStatus = "running"
+	RunnerStatusCompleted  RunnerStatus = "completed"
+	RunnerStatusDeleted    RunnerStatus = "deleted"
+)
+
+// JobResultStatus is the outcome recorded on a Job once CompleteJob has been called
+type JobResultStatus string
+
+// JobResultStatus values
+const (
+	JobResultStatusUnknown JobResultStatus = "unknown"
+	JobResultStatusSuccess JobResultStatus = "success"
+	JobResultStatusFailure JobResultStatus = "failure"
+)
+
+// Target is a scope (GitHub organization, repository or enterprise) myshoes dispatches jobs for
+type Target struct {
+	UUID                uuid.UUID      `db:"uuid"`
+	Scope               string         `db:"scope"`
+	GHEDomain           sql.NullString `db:"ghe_domain"`
+	GitHubPersonalToken string         `db:"github_personal_token"`
+	GitHubToken         string         `db:"-"`
+	TokenExpiredAt      time.Time      `db:"-"`
+	ResourceType        ResourceType   `db:"resource_type"`
+	ProviderURL         sql.NullString `db:"provider_url"`
+	RunnerUser          sql.NullString `db:"runner_user"`
+	Status              TargetStatus   `db:"status"`
+	StatusDescription   sql.NullString `db:"status_description"`
+	// Labels are key/value labels advertised by this target, e.g. {"arch": "amd64", "gpu": "*"}.
+	// Used to pick the right target among several that could otherwise serve the same repo scope.
+	Labels TargetLabels `db:"labels"`
+	// JobRetention overrides config.Config.DefaultJobRetention for jobs dispatched to this
+	// target. Zero means no override: fall back to the process-wide default. See
+	// NewJobFromWorkflowJobEvent, where this is resolved into Job.Retention.
+	JobRetention time.Duration `db:"job_retention"`
+	CreatedAt    time.Time     `db:"created_at"`
+	UpdatedAt    time.Time     `db:"updated_at"`
+}
+
+// Job is a single queued GitHub Actions job waiting for (or already assigned) a runner
+type Job struct {
+	UUID           uuid.UUID      `db:"uuid"`
+	GHEDomain      sql.NullString `db:"ghe_domain"`
+	Repository     string         `db:"repository"`
+	CheckEventJSON string         `db:"check_event"`
+	TargetID       uuid.UUID      `db:"target_id"`
+	// RequiredLabels are the labels requested via the workflow's `runs-on`, used to pick a target
+	// whose own Labels are a superset. See SearchRepoWithLabels.
+	RequiredLabels TargetLabels `db:"required_labels"`
+	CreatedAt      time.Time    `db:"created_at"`
+
+	// CompletedAt, Result and ResultStatus are set by CompleteJob once a runner has finished the
+	// job, so operators can inspect what happened after the fact. They are zero until then.
+	// Retention is normally set at enqueue time (see NewJobFromWorkflowJobEvent); CompleteJob
+	// fills in config.Config.DefaultJobRetention only if it is still zero.
+	CompletedAt  time.Time       `db:"completed_at"`
+	Retention    time.Duration   `db:"retention"`
+	Result       []byte          `db:"result"`
+	ResultStatus JobResultStatus `db:"result_status"`
+}
+
+// Runner is a single runner instance registered against a Target
+type Runner struct {
+	UUID          uuid.UUID      `db:"uuid"`
+	TargetID      uuid.UUID      `db:"target_id"`
+	RepositoryURL string         `db:"repository_url"`
+	RunnerUser    sql.NullString `db:"runner_user"`
+	ResourceType  ResourceType   `db:"resource_type"`
+	Status        RunnerStatus   `db:"status"`
+	CreatedAt     time.Time      `db:"created_at"`
+}
+
+// Datastore is the persistence interface implemented by pkg/datastore/memory and
+// pkg/datastore/mysql
+type Datastore interface {
+	CreateTarget(ctx context.Context, target Target) error
+	GetTarget(ctx context.Context, id uuid.UUID) (*Target, error)
+	GetTargetByScope(ctx context.Context, gheDomain, scope string) (*Target, error)
+	ListTargets(ctx context.Context) ([]Target, error)
+	DeleteTarget(ctx context.Context, id uuid.UUID) error
+	UpdateTargetStatus(ctx context.Context, targetID uuid.UUID, newStatus TargetStatus, description string) error
+	UpdateToken(ctx context.Context, targetID uuid.UUID, newToken string, newExpiredAt time.Time) error
+	UpdateTargetParam(ctx context.Context, targetID uuid.UUID, newResourceType ResourceType, newProviderURL string) error
+	UpdateTargetLabels(ctx context.Context, targetID uuid.UUID, labels TargetLabels) error
+
+	EnqueueJob(ctx context.Context, job Job) error
+	ListJobs(ctx context.Context) ([]Job, error)
+	GetJob(ctx context.Context, id uuid.UUID) (*Job, error)
+	CompleteJob(ctx context.Context, id uuid.UUID, result []byte, status JobResultStatus) error
+	ListCompletedJobs(ctx context.Context) ([]Job, error)
+	ListCompletedJobsBefore(ctx context.Context, now time.Time) ([]Job, error)
+	DeleteJob(ctx context.Context, id uuid.UUID) error
+
+	CreateRunner(ctx context.Context, runner Runner) error
+	ListRunners(ctx context.Context) ([]Runner, error)
+	ListRunnersByTargetID(ctx context.Context, targetID uuid.UUID) ([]Runner, error)
+	ListRunnersLogByUntil(ctx context.Context, until time.Time) ([]Runner, error)
+	ListRunnersLogBySince(ctx context.Context, since time.Time) ([]Runner, error)
+	GetRunner(ctx context.Context, id uuid.UUID) (*Runner, error)
+	DeleteRunner(ctx context.Context, id uuid.UUID, deletedAt time.Time, reason RunnerStatus) error
+
+	GetLock(ctx context.Context) error
+	IsLocked(ctx context.Context) (string, error)
+}
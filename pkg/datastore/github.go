@@ -6,14 +6,27 @@ import (
 	"net/url"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/whywaita/myshoes/pkg/logger"
 
 	"github.com/google/go-github/v47/github"
+	"github.com/whywaita/myshoes/internal/config"
 	"github.com/whywaita/myshoes/pkg/gh"
 )
 
+// defaultMaxConcurrencyPendingScan is used when config.Config.MaxConcurrencyPendingScan is not set.
+const defaultMaxConcurrencyPendingScan = 10
+
+// newClientInstallationByRepoFunc and getPendingRunByRepoFunc are indirections over the
+// package-level functions they are named after, so tests can stub the GitHub-facing
+// parts of the scan without a real GitHub API.
+var (
+	newClientInstallationByRepoFunc = NewClientInstallationByRepo
+	getPendingRunByRepoFunc         = getPendingRunByRepo
+)
+
 // NewClientInstallationByRepo create a client of GitHub using installation ID from repo name
 func NewClientInstallationByRepo(ctx context.Context, ds Datastore, repo string) (*github.Client, *Target, error) {
 	target, err := SearchRepo(ctx, ds, repo)
@@ -40,61 +53,93 @@ type PendingWorkflowRunWithTarget struct {
 	WorkflowRun *github.WorkflowRun
 }
 
-// GetPendingWorkflowRunByRecentRepositories get pending workflow runs by recent active repositories
+// GetPendingWorkflowRunByRecentRepositories get pending workflow runs by recent active repositories.
+// Repositories are scanned concurrently (bounded by config.Config.MaxConcurrencyPendingScan) since
+// each repository requires one or more round-trips to the GitHub API.
 func GetPendingWorkflowRunByRecentRepositories(ctx context.Context, ds Datastore) ([]PendingWorkflowRunWithTarget, error) {
 	recentActiveRepositories, err := getRecentRepositories(ctx, ds)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get recent repositories: %w", err)
 	}
 
-	var pendingRuns []PendingWorkflowRunWithTarget
-	for _, repoRawURL := range recentActiveRepositories {
+	concurrency := config.Config.MaxConcurrencyPendingScan
+	if concurrency < 1 {
+		concurrency = defaultMaxConcurrencyPendingScan
+	}
+
+	results := make([][]PendingWorkflowRunWithTarget, len(recentActiveRepositories))
+	var mu sync.Mutex
+
+	_ = ForEachJob(ctx, len(recentActiveRepositories), concurrency, func(ctx context.Context, i int) error {
+		repoRawURL := recentActiveRepositories[i]
+
 		u, err := url.Parse(repoRawURL)
 		if err != nil {
 			logger.Logf(false, "failed to scrape pending run: failed to parse repository url: %+v", err)
-			continue
+			return nil
 		}
 		fullName := strings.TrimPrefix(u.Path, "/")
-		client, target, err := NewClientInstallationByRepo(ctx, ds, fullName)
+		client, target, err := newClientInstallationByRepoFunc(ctx, ds, fullName)
 		if err != nil {
 			logger.Logf(false, "failed to scrape pending run: failed to create a client of GitHub by repo (full_name: %s) %+v", fullName, err)
-			continue
+			return nil
 		}
 
+		policy, _ := config.Config.PendingPolicy.(PendingPolicy)
+		if policy == nil {
+			policy = AgeBasedPolicy{MinAge: defaultPendingMinAge}
+		}
+		if !policy.ShouldScan(ctx, target) {
+			logger.Logf(true, "skipping pending run scan for %s: policy declined to scan", fullName)
+			recordPendingScanStatus(target.UUID, PendingScanStatus{Threshold: policy.Threshold(target), Skipped: true})
+			return nil
+		}
+		recordPendingScanStatus(target.UUID, PendingScanStatus{Threshold: policy.Threshold(target), Skipped: false})
+
 		owner, repo := gh.DivideScope(fullName)
-		pendingRunsByRepo, err := getPendingRunByRepo(ctx, client, owner, repo)
+		pendingRunsByRepo, err := getPendingRunByRepoFunc(ctx, client, owner, repo, policy, target)
 		if err != nil {
 			logger.Logf(false, "failed to scrape pending run: failed to get pending run by repo (full_name: %s) %+v", fullName, err)
-			continue
+			return nil
 		}
+
+		var runs []PendingWorkflowRunWithTarget
 		for _, run := range pendingRunsByRepo {
-			pendingRuns = append(pendingRuns, PendingWorkflowRunWithTarget{
+			runs = append(runs, PendingWorkflowRunWithTarget{
 				Target:      target,
 				WorkflowRun: run,
 			})
 		}
+
+		mu.Lock()
+		results[i] = runs
+		mu.Unlock()
+
+		return nil
+	})
+
+	var pendingRuns []PendingWorkflowRunWithTarget
+	for _, runs := range results {
+		pendingRuns = append(pendingRuns, runs...)
 	}
 
 	return pendingRuns, nil
 }
 
-func getPendingRunByRepo(ctx context.Context, client *github.Client, owner, repo string) ([]*github.WorkflowRun, error) {
+func getPendingRunByRepo(ctx context.Context, client *github.Client, owner, repo string, policy PendingPolicy, target *Target) ([]*github.WorkflowRun, error) {
 	runs, err := gh.ListWorkflowRunsNewestOneHundred(ctx, client, owner, repo)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list runs: %w", err)
 	}
 
+	threshold := policy.Threshold(target)
 	var pendingRuns []*github.WorkflowRun
 	for _, r := range runs {
-		if r.GetStatus() == "queued" || r.GetStatus() == "pending" {
-			oldMinutes := 30
-			sinceMinutes := time.Since(r.CreatedAt.Time).Minutes()
-			if sinceMinutes >= float64(oldMinutes) {
-				logger.Logf(false, "run %d is pending over %d minutes, So will enqueue", r.GetID(), oldMinutes)
-				pendingRuns = append(pendingRuns, r)
-			} else {
-				logger.Logf(true, "run %d is pending, but not over %d minutes. So ignore (since: %f minutes)", r.GetID(), oldMinutes, sinceMinutes)
-			}
+		if isPendingRun(policy, target, r) {
+			logger.Logf(false, "run %d is pending over %s, So will enqueue", r.GetID(), threshold)
+			pendingRuns = append(pendingRuns, r)
+		} else if r.GetStatus() == "queued" || r.GetStatus() == "pending" {
+			logger.Logf(true, "run %d is pending, but not over %s. So ignore (since: %s)", r.GetID(), threshold, time.Since(r.CreatedAt.Time))
 		}
 	}
 
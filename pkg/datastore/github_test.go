@@ -0,0 +1,147 @@
+package datastore
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/google/go-github/v47/github"
+)
+
+// fakeDatastore embeds Datastore so it only needs to implement the methods
+// exercised by the function under test.
+type fakeDatastore struct {
+	Datastore
+	runners []Runner
+}
+
+func (f *fakeDatastore) ListRunnersLogBySince(ctx context.Context, since time.Time) ([]Runner, error) {
+	return f.runners, nil
+}
+
+func newFakeRunner(repoURL string) Runner {
+	return Runner{
+		UUID:          uuid.NewV4(),
+		RepositoryURL: repoURL,
+		CreatedAt:     time.Now(),
+	}
+}
+
+func TestGetPendingWorkflowRunByRecentRepositories_OrderIndependent(t *testing.T) {
+	ds := &fakeDatastore{
+		runners: []Runner{
+			newFakeRunner("https://github.com/octocat/repo1"),
+			newFakeRunner("https://github.com/octocat/repo2"),
+			newFakeRunner("https://github.com/octocat/repo3"),
+		},
+	}
+
+	origNewClient := newClientInstallationByRepoFunc
+	origGetPending := getPendingRunByRepoFunc
+	defer func() {
+		newClientInstallationByRepoFunc = origNewClient
+		getPendingRunByRepoFunc = origGetPending
+	}()
+
+	newClientInstallationByRepoFunc = func(ctx context.Context, ds Datastore, repo string) (*github.Client, *Target, error) {
+		return nil, &Target{Scope: repo}, nil
+	}
+	getPendingRunByRepoFunc = func(ctx context.Context, client *github.Client, owner, repo string, policy PendingPolicy, target *Target) ([]*github.WorkflowRun, error) {
+		run := &github.WorkflowRun{}
+		run.ID = github.Int64(1)
+		return []*github.WorkflowRun{run}, nil
+	}
+
+	got, err := GetPendingWorkflowRunByRecentRepositories(context.Background(), ds)
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if len(got) != len(ds.runners) {
+		t.Fatalf("want %d pending runs, got %d", len(ds.runners), len(got))
+	}
+	seen := map[string]int{}
+	for _, r := range got {
+		seen[r.Target.Scope]++
+	}
+	for _, want := range []string{"octocat/repo1", "octocat/repo2", "octocat/repo3"} {
+		if seen[want] != 1 {
+			t.Errorf("want exactly one pending run for %s, got %d", want, seen[want])
+		}
+	}
+}
+
+func TestGetPendingWorkflowRunByRecentRepositories_SlowRepoDoesNotBlockOthers(t *testing.T) {
+	runners := make([]Runner, 0, 20)
+	for i := 0; i < 20; i++ {
+		runners = append(runners, newFakeRunner(fmt.Sprintf("https://github.com/octocat/repo%d", i)))
+	}
+	ds := &fakeDatastore{runners: runners}
+
+	origNewClient := newClientInstallationByRepoFunc
+	origGetPending := getPendingRunByRepoFunc
+	defer func() {
+		newClientInstallationByRepoFunc = origNewClient
+		getPendingRunByRepoFunc = origGetPending
+	}()
+
+	var inFlight int32
+	newClientInstallationByRepoFunc = func(ctx context.Context, ds Datastore, repo string) (*github.Client, *Target, error) {
+		return nil, &Target{Scope: repo}, nil
+	}
+	getPendingRunByRepoFunc = func(ctx context.Context, client *github.Client, owner, repo string, policy PendingPolicy, target *Target) ([]*github.WorkflowRun, error) {
+		// Every repo takes some time, so a sequential scan (200ms + 19*100ms) exceeds the 1s
+		// bound below, while a concurrent one finishes well within it.
+		if owner == "octocat" && repo == "repo0" {
+			time.Sleep(200 * time.Millisecond)
+		} else {
+			time.Sleep(100 * time.Millisecond)
+		}
+		atomic.AddInt32(&inFlight, 1)
+		return nil, nil
+	}
+
+	start := time.Now()
+	if _, err := GetPendingWorkflowRunByRecentRepositories(context.Background(), ds); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	elapsed := time.Since(start)
+
+	if elapsed >= 1*time.Second {
+		t.Fatalf("expected the slow repo to run concurrently with the others, took %s", elapsed)
+	}
+	if atomic.LoadInt32(&inFlight) != int32(len(runners)) {
+		t.Fatalf("want all %d repos scanned, got %d", len(runners), inFlight)
+	}
+}
+
+func TestGetPendingWorkflowRunByRecentRepositories_ContextCancelled(t *testing.T) {
+	runners := []Runner{newFakeRunner("https://github.com/octocat/repo1")}
+	ds := &fakeDatastore{runners: runners}
+
+	origNewClient := newClientInstallationByRepoFunc
+	defer func() { newClientInstallationByRepoFunc = origNewClient }()
+
+	var called int32
+	newClientInstallationByRepoFunc = func(ctx context.Context, ds Datastore, repo string) (*github.Client, *Target, error) {
+		atomic.AddInt32(&called, 1)
+		return nil, &Target{Scope: repo}, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	got, err := GetPendingWorkflowRunByRecentRepositories(ctx, ds)
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("want no pending runs once context is cancelled, got %d", len(got))
+	}
+	if atomic.LoadInt32(&called) != 0 {
+		t.Fatalf("want scan to abort before calling GitHub, called %d times", called)
+	}
+}
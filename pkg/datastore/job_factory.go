@@ -0,0 +1,50 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v47/github"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/internal/config"
+)
+
+// NewJobFromWorkflowJobEvent builds the Job to enqueue for an incoming `workflow_job` webhook
+// event, resolving which Target should run it from the event's repository and the `runs-on`
+// labels GitHub Actions recorded on the job. See ParseRunsOnLabels and SearchRepoWithLabels.
+// The job's Retention is resolved here too, from the target's JobRetention override (falling
+// back to config.Config.DefaultJobRetention), so it is fixed at enqueue time and consistent
+// regardless of which datastore backend eventually calls CompleteJob.
+func NewJobFromWorkflowJobEvent(ctx context.Context, ds Datastore, event *github.WorkflowJobEvent) (*Job, error) {
+	repo := event.GetRepo().GetFullName()
+	requiredLabels := ParseRunsOnLabels(event.GetWorkflowJob().Labels)
+
+	target, err := SearchRepoWithLabels(ctx, ds, repo, requiredLabels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search target for repository (repo: %s): %w", repo, err)
+	}
+
+	checkEvent, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal workflow_job event: %w", err)
+	}
+
+	retention := target.JobRetention
+	if retention == 0 {
+		retention = config.Config.DefaultJobRetention
+	}
+
+	return &Job{
+		UUID:           uuid.NewV4(),
+		GHEDomain:      target.GHEDomain,
+		Repository:     repo,
+		CheckEventJSON: string(checkEvent),
+		TargetID:       target.UUID,
+		RequiredLabels: requiredLabels,
+		CreatedAt:      time.Now(),
+		Retention:      retention,
+	}, nil
+}
@@ -0,0 +1,53 @@
+package datastore
+
+import (
+	"sync"
+	"time"
+)
+
+// jobWaitBucketBounds mirrors prometheus.DefBuckets. It is duplicated here, rather than
+// imported, so pkg/datastore does not need to depend on prometheus: pkg/metric already depends
+// on pkg/datastore, and reading the accumulator this package exposes is how it stays that way
+// round rather than the reverse.
+var jobWaitBucketBounds = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+var (
+	jobWaitMu      sync.Mutex
+	jobWaitBuckets = map[float64]uint64{}
+	jobWaitSum     float64
+	jobWaitCount   uint64
+)
+
+// RecordJobAssigned records that a job waited wait between being created and a runner being
+// assigned to it. Call this exactly once per job, at the moment a runner is assigned (see
+// runner.AssignJob), not on every poll of the queue: unlike the queue depth, wait time is an
+// event that happens once per job, and a histogram fed from it is monotonically increasing as
+// myshoes expects of a Prometheus histogram.
+func RecordJobAssigned(wait time.Duration) {
+	jobWaitMu.Lock()
+	defer jobWaitMu.Unlock()
+
+	seconds := wait.Seconds()
+	jobWaitSum += seconds
+	jobWaitCount++
+	for _, b := range jobWaitBucketBounds {
+		if seconds <= b {
+			jobWaitBuckets[b]++
+		}
+	}
+}
+
+// GetJobWaitHistogram returns a snapshot of every job-wait observation recorded so far via
+// RecordJobAssigned, as cumulative per-bucket counts alongside the overall sum and count. It is
+// read by pkg/metric to export myshoes_job_wait_seconds.
+func GetJobWaitHistogram() (buckets map[float64]uint64, sum float64, count uint64) {
+	jobWaitMu.Lock()
+	defer jobWaitMu.Unlock()
+
+	snapshot := make(map[float64]uint64, len(jobWaitBuckets))
+	for b, c := range jobWaitBuckets {
+		snapshot[b] = c
+	}
+
+	return snapshot, jobWaitSum, jobWaitCount
+}
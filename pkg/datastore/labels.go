@@ -0,0 +1,109 @@
+package datastore
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// TargetLabels is the set of key/value labels advertised by a Target, e.g. {"arch": "amd64",
+// "gpu": "*"}. It is stored as a JSON object in MySQL and implements driver.Valuer / sql.Scanner
+// to do that encoding transparently.
+type TargetLabels map[string]string
+
+// Value implements driver.Valuer
+func (l TargetLabels) Value() (driver.Value, error) {
+	if l == nil {
+		return "{}", nil
+	}
+
+	b, err := json.Marshal(l)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal labels: %w", err)
+	}
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner
+func (l *TargetLabels) Scan(src interface{}) error {
+	if src == nil {
+		*l = TargetLabels{}
+		return nil
+	}
+
+	var b []byte
+	switch v := src.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type for TargetLabels: %T", src)
+	}
+
+	if len(b) == 0 {
+		*l = TargetLabels{}
+		return nil
+	}
+
+	var labels TargetLabels
+	if err := json.Unmarshal(b, &labels); err != nil {
+		return fmt.Errorf("failed to unmarshal labels: %w", err)
+	}
+	*l = labels
+	return nil
+}
+
+// MatchLabels reports whether targetLabels satisfy requiredLabels, i.e. every key required by a
+// job is present on the target and its value matches. Target label values may use glob
+// expressions (as matched by filepath.Match, e.g. "gpu=*" is expressed as the label value "*"), so
+// an operator can advertise "arch=amd64,gpu=*" and have it satisfy any required gpu value.
+func MatchLabels(targetLabels, requiredLabels TargetLabels) bool {
+	for key, want := range requiredLabels {
+		have, ok := targetLabels[key]
+		if !ok {
+			return false
+		}
+
+		matched, err := filepath.Match(have, want)
+		if err != nil || !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ParseRunsOnLabels turns the `runs-on` array of an incoming workflow webhook into the
+// key/value label set persisted on a Job's RequiredLabels. Standard GitHub labels (e.g.
+// "self-hosted", "linux") are recorded as boolean-style labels (value "true"); custom labels
+// already in "key=value" form (e.g. "arch=amd64") are split and kept as-is.
+func ParseRunsOnLabels(runsOn []string) TargetLabels {
+	labels := make(TargetLabels, len(runsOn))
+	for _, raw := range runsOn {
+		key, value, ok := strings.Cut(raw, "=")
+		if !ok {
+			labels[raw] = "true"
+			continue
+		}
+		labels[key] = value
+	}
+
+	return labels
+}
+
+// SelectTargetByLabels picks the first target, in the order given, whose labels are a superset
+// of requiredLabels from a set of candidates that already satisfy a repository's scope. It is
+// used by SearchRepoWithLabels, whose candidates are at most one target registered for the
+// repository and one registered for its owning organization.
+func SelectTargetByLabels(candidates []Target, requiredLabels TargetLabels) (*Target, error) {
+	for i, t := range candidates {
+		if MatchLabels(t.Labels, requiredLabels) {
+			return &candidates[i], nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
@@ -0,0 +1,70 @@
+package datastore
+
+import "testing"
+
+func TestMatchLabels(t *testing.T) {
+	tests := []struct {
+		name           string
+		targetLabels   TargetLabels
+		requiredLabels TargetLabels
+		want           bool
+	}{
+		{
+			name:           "no required labels always matches",
+			targetLabels:   TargetLabels{"arch": "amd64"},
+			requiredLabels: TargetLabels{},
+			want:           true,
+		},
+		{
+			name:           "exact match",
+			targetLabels:   TargetLabels{"arch": "amd64", "self-hosted": "true"},
+			requiredLabels: TargetLabels{"arch": "amd64"},
+			want:           true,
+		},
+		{
+			name:           "missing key",
+			targetLabels:   TargetLabels{"arch": "amd64"},
+			requiredLabels: TargetLabels{"gpu": "true"},
+			want:           false,
+		},
+		{
+			name:           "glob value on target",
+			targetLabels:   TargetLabels{"gpu": "*"},
+			requiredLabels: TargetLabels{"gpu": "a100"},
+			want:           true,
+		},
+		{
+			name:           "value mismatch",
+			targetLabels:   TargetLabels{"arch": "arm64"},
+			requiredLabels: TargetLabels{"arch": "amd64"},
+			want:           false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MatchLabels(tt.targetLabels, tt.requiredLabels)
+			if got != tt.want {
+				t.Errorf("MatchLabels() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRunsOnLabels(t *testing.T) {
+	got := ParseRunsOnLabels([]string{"self-hosted", "linux", "arch=amd64"})
+	want := TargetLabels{
+		"self-hosted": "true",
+		"linux":       "true",
+		"arch":        "amd64",
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("ParseRunsOnLabels() = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("ParseRunsOnLabels()[%q] = %q, want %q", k, got[k], v)
+		}
+	}
+}
@@ -9,15 +9,17 @@ import (
 
 	uuid "github.com/satori/go.uuid"
 
+	"github.com/whywaita/myshoes/internal/config"
 	"github.com/whywaita/myshoes/pkg/datastore"
 )
 
 // Memory is implement datastore on-memory
 type Memory struct {
-	mu      *sync.RWMutex
-	targets map[uuid.UUID]datastore.Target
-	jobs    map[uuid.UUID]datastore.Job
-	runners map[uuid.UUID]datastore.Runner
+	mu            *sync.RWMutex
+	targets       map[uuid.UUID]datastore.Target
+	jobs          map[uuid.UUID]datastore.Job
+	completedJobs map[uuid.UUID]datastore.Job
+	runners       map[uuid.UUID]datastore.Runner
 }
 
 // New create map
@@ -25,13 +27,15 @@ func New() (*Memory, error) {
 	m := &sync.RWMutex{}
 	t := map[uuid.UUID]datastore.Target{}
 	j := map[uuid.UUID]datastore.Job{}
+	cj := map[uuid.UUID]datastore.Job{}
 	r := map[uuid.UUID]datastore.Runner{}
 
 	return &Memory{
-		mu:      m,
-		targets: t,
-		jobs:    j,
-		runners: r,
+		mu:            m,
+		targets:       t,
+		jobs:          j,
+		completedJobs: cj,
+		runners:       r,
 	}, nil
 }
 
@@ -57,12 +61,12 @@ func (m *Memory) GetTarget(ctx context.Context, id uuid.UUID) (*datastore.Target
 }
 
 // GetTargetByScope get a target from scope
-func (m *Memory) GetTargetByScope(ctx context.Context, scope string) (*datastore.Target, error) {
+func (m *Memory) GetTargetByScope(ctx context.Context, gheDomain, scope string) (*datastore.Target, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
 	for _, t := range m.targets {
-		if t.Scope == scope {
+		if t.Scope == scope && t.GHEDomain.String == gheDomain {
 			// found
 			return &t, nil
 
@@ -153,6 +157,21 @@ func (m *Memory) UpdateTargetParam(ctx context.Context, targetID uuid.UUID, newR
 	return nil
 }
 
+// UpdateTargetLabels update the labels of a target
+func (m *Memory) UpdateTargetLabels(ctx context.Context, targetID uuid.UUID, labels datastore.TargetLabels) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, ok := m.targets[targetID]
+	if !ok {
+		return datastore.ErrNotFound
+	}
+	t.Labels = labels
+
+	m.targets[targetID] = t
+	return nil
+}
+
 // EnqueueJob add a job
 func (m *Memory) EnqueueJob(ctx context.Context, job datastore.Job) error {
 	m.mu.Lock()
@@ -181,9 +200,78 @@ func (m *Memory) DeleteJob(ctx context.Context, id uuid.UUID) error {
 	defer m.mu.Unlock()
 
 	delete(m.jobs, id)
+	delete(m.completedJobs, id)
+	return nil
+}
+
+// GetJob get a job, looking in both the pending queue and completed jobs not yet deleted
+func (m *Memory) GetJob(ctx context.Context, id uuid.UUID) (*datastore.Job, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if j, ok := m.jobs[id]; ok {
+		return &j, nil
+	}
+	if j, ok := m.completedJobs[id]; ok {
+		return &j, nil
+	}
+
+	return nil, datastore.ErrNotFound
+}
+
+// CompleteJob move a job out of the pending queue and record its result, keeping it around for
+// its retention period so operators can inspect what happened after the fact.
+func (m *Memory) CompleteJob(ctx context.Context, id uuid.UUID, result []byte, status datastore.JobResultStatus) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j, ok := m.jobs[id]
+	if !ok {
+		return datastore.ErrNotFound
+	}
+
+	j.CompletedAt = time.Now()
+	j.Result = result
+	j.ResultStatus = status
+	if j.Retention == 0 {
+		j.Retention = config.Config.DefaultJobRetention
+	}
+
+	delete(m.jobs, id)
+	m.completedJobs[id] = j
+
 	return nil
 }
 
+// ListCompletedJobs get all completed jobs not yet deleted, including those whose retention
+// period has elapsed but that the retention sweeper has not removed yet
+func (m *Memory) ListCompletedJobs(ctx context.Context) ([]datastore.Job, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var jobs []datastore.Job
+	for _, j := range m.completedJobs {
+		jobs = append(jobs, j)
+	}
+
+	return jobs, nil
+}
+
+// ListCompletedJobsBefore get completed jobs whose retention period has elapsed as of now
+func (m *Memory) ListCompletedJobsBefore(ctx context.Context, now time.Time) ([]datastore.Job, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var jobs []datastore.Job
+	for _, j := range m.completedJobs {
+		if j.CompletedAt.Add(j.Retention).Before(now) {
+			jobs = append(jobs, j)
+		}
+	}
+
+	return jobs, nil
+}
+
 // CreateRunner add a runner
 func (m *Memory) CreateRunner(ctx context.Context, runner datastore.Runner) error {
 	m.mu.Lock()
@@ -237,6 +325,21 @@ func (m *Memory) ListRunnersLogByUntil(ctx context.Context, until time.Time) ([]
 	return runners, nil
 }
 
+// ListRunnersLogBySince get a runners created since time
+func (m *Memory) ListRunnersLogBySince(ctx context.Context, since time.Time) ([]datastore.Runner, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var runners []datastore.Runner
+	for _, r := range m.runners {
+		if r.CreatedAt.After(since) {
+			runners = append(runners, r)
+		}
+	}
+
+	return runners, nil
+}
+
 // GetRunner get a runner
 func (m *Memory) GetRunner(ctx context.Context, id uuid.UUID) (*datastore.Runner, error) {
 	m.mu.Lock()
@@ -268,3 +371,5 @@ func (m *Memory) GetLock(ctx context.Context) error {
 func (m *Memory) IsLocked(ctx context.Context) (string, error) {
 	return datastore.IsNotLocked, nil
 }
+
+var _ datastore.Datastore = (*Memory)(nil)
@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/internal/config"
+	"github.com/whywaita/myshoes/pkg/datastore"
+)
+
+func TestMemory_CompleteJob_RoundTrip(t *testing.T) {
+	m, err := New()
+	if err != nil {
+		t.Fatalf("failed to create memory datastore: %+v", err)
+	}
+	ctx := context.Background()
+
+	job := datastore.Job{
+		UUID:      uuid.NewV4(),
+		CreatedAt: time.Now(),
+		Retention: time.Hour,
+	}
+	if err := m.EnqueueJob(ctx, job); err != nil {
+		t.Fatalf("failed to enqueue job: %+v", err)
+	}
+
+	if err := m.CompleteJob(ctx, job.UUID, []byte("ok"), datastore.JobResultStatusSuccess); err != nil {
+		t.Fatalf("failed to complete job: %+v", err)
+	}
+
+	pending, err := m.ListJobs(ctx)
+	if err != nil {
+		t.Fatalf("failed to list jobs: %+v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("want 0 pending jobs after CompleteJob, got %d", len(pending))
+	}
+
+	completed, err := m.ListCompletedJobs(ctx)
+	if err != nil {
+		t.Fatalf("failed to list completed jobs: %+v", err)
+	}
+	if len(completed) != 1 || completed[0].ResultStatus != datastore.JobResultStatusSuccess {
+		t.Fatalf("want 1 completed job with status success, got %+v", completed)
+	}
+
+	got, err := m.GetJob(ctx, job.UUID)
+	if err != nil {
+		t.Fatalf("failed to get completed job: %+v", err)
+	}
+	if got.ResultStatus != datastore.JobResultStatusSuccess {
+		t.Errorf("GetJob ResultStatus = %s, want %s", got.ResultStatus, datastore.JobResultStatusSuccess)
+	}
+
+	stillWithinRetention, err := m.ListCompletedJobsBefore(ctx, time.Now())
+	if err != nil {
+		t.Fatalf("failed to list completed jobs before now: %+v", err)
+	}
+	if len(stillWithinRetention) != 0 {
+		t.Errorf("want 0 expired jobs while within retention, got %d", len(stillWithinRetention))
+	}
+
+	expired, err := m.ListCompletedJobsBefore(ctx, time.Now().Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("failed to list completed jobs before expiry: %+v", err)
+	}
+	if len(expired) != 1 {
+		t.Fatalf("want 1 expired job once retention has elapsed, got %d", len(expired))
+	}
+
+	if err := m.DeleteJob(ctx, job.UUID); err != nil {
+		t.Fatalf("failed to delete job: %+v", err)
+	}
+	if _, err := m.GetJob(ctx, job.UUID); err != datastore.ErrNotFound {
+		t.Errorf("GetJob after DeleteJob = %v, want %v", err, datastore.ErrNotFound)
+	}
+}
+
+func TestMemory_CompleteJob_FallsBackToDefaultRetention(t *testing.T) {
+	original := config.Config.DefaultJobRetention
+	config.Config.DefaultJobRetention = 24 * time.Hour
+	defer func() { config.Config.DefaultJobRetention = original }()
+
+	m, err := New()
+	if err != nil {
+		t.Fatalf("failed to create memory datastore: %+v", err)
+	}
+	ctx := context.Background()
+
+	job := datastore.Job{UUID: uuid.NewV4(), CreatedAt: time.Now()}
+	if err := m.EnqueueJob(ctx, job); err != nil {
+		t.Fatalf("failed to enqueue job: %+v", err)
+	}
+	if err := m.CompleteJob(ctx, job.UUID, nil, datastore.JobResultStatusFailure); err != nil {
+		t.Fatalf("failed to complete job: %+v", err)
+	}
+
+	got, err := m.GetJob(ctx, job.UUID)
+	if err != nil {
+		t.Fatalf("failed to get completed job: %+v", err)
+	}
+	if got.Retention == 0 {
+		t.Error("want CompleteJob to fall back to config.Config.DefaultJobRetention when Retention is unset, got 0")
+	}
+}
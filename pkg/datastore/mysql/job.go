@@ -0,0 +1,99 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/internal/config"
+	"github.com/whywaita/myshoes/pkg/datastore"
+)
+
+// EnqueueJob add a job
+func (m *MySQL) EnqueueJob(ctx context.Context, job datastore.Job) error {
+	query := `INSERT INTO jobs(uuid, ghe_domain, repository, check_event, target_id, required_labels, created_at, retention) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	if _, err := m.Conn.ExecContext(ctx, query, job.UUID, job.GHEDomain, job.Repository, job.CheckEventJSON, job.TargetID, job.RequiredLabels, job.CreatedAt, job.Retention); err != nil {
+		return fmt.Errorf("failed to execute INSERT query: %w", err)
+	}
+
+	return nil
+}
+
+// ListJobs get all jobs that are still waiting to be picked up by a runner
+func (m *MySQL) ListJobs(ctx context.Context) ([]datastore.Job, error) {
+	var jobs []datastore.Job
+	query := `SELECT uuid, ghe_domain, repository, check_event, target_id, required_labels, created_at FROM jobs WHERE completed_at IS NULL`
+	if err := m.Conn.SelectContext(ctx, &jobs, query); err != nil {
+		return nil, fmt.Errorf("failed to execute SELECT query: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// GetJob get a job, looking in both the pending queue and completed jobs not yet deleted
+func (m *MySQL) GetJob(ctx context.Context, id uuid.UUID) (*datastore.Job, error) {
+	var j datastore.Job
+	query := `SELECT uuid, ghe_domain, repository, check_event, target_id, required_labels, created_at, completed_at, retention, result, result_status FROM jobs WHERE uuid = ?`
+	if err := m.Conn.GetContext(ctx, &j, query, id.String()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, datastore.ErrNotFound
+		}
+
+		return nil, fmt.Errorf("failed to execute SELECT query: %w", err)
+	}
+
+	return &j, nil
+}
+
+// CompleteJob record the result of a job and keep it around for its retention period so
+// operators can inspect what happened after the fact, instead of deleting it immediately.
+// retention is normally already set by NewJobFromWorkflowJobEvent at enqueue time; the
+// IF(retention = 0, ...) fallback only covers jobs enqueued without going through it, mirroring
+// memory.Memory.CompleteJob's equivalent fallback.
+func (m *MySQL) CompleteJob(ctx context.Context, id uuid.UUID, result []byte, status datastore.JobResultStatus) error {
+	query := `UPDATE jobs SET completed_at = ?, result = ?, result_status = ?, retention = IF(retention = 0, ?, retention) WHERE uuid = ?`
+	if _, err := m.Conn.ExecContext(ctx, query, time.Now(), result, status, config.Config.DefaultJobRetention, id.String()); err != nil {
+		return fmt.Errorf("failed to execute UPDATE query: %w", err)
+	}
+
+	return nil
+}
+
+// ListCompletedJobs get all completed jobs not yet deleted, including those whose retention
+// period has elapsed but that the retention sweeper has not removed yet
+func (m *MySQL) ListCompletedJobs(ctx context.Context) ([]datastore.Job, error) {
+	var jobs []datastore.Job
+	query := `SELECT uuid, ghe_domain, repository, check_event, target_id, required_labels, created_at, completed_at, retention, result, result_status FROM jobs WHERE completed_at IS NOT NULL`
+	if err := m.Conn.SelectContext(ctx, &jobs, query); err != nil {
+		return nil, fmt.Errorf("failed to execute SELECT query: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// ListCompletedJobsBefore get completed jobs whose retention period has elapsed as of now.
+// retention is stored as a raw time.Duration (nanoseconds), matching the Go field it scans
+// into, so the comparison converts it down to whole seconds for the INTERVAL arithmetic.
+func (m *MySQL) ListCompletedJobsBefore(ctx context.Context, now time.Time) ([]datastore.Job, error) {
+	var jobs []datastore.Job
+	query := `SELECT uuid, ghe_domain, repository, check_event, target_id, required_labels, created_at, completed_at, retention, result, result_status FROM jobs WHERE completed_at IS NOT NULL AND completed_at + INTERVAL (retention DIV 1000000000) SECOND < ?`
+	if err := m.Conn.SelectContext(ctx, &jobs, query, now); err != nil {
+		return nil, fmt.Errorf("failed to execute SELECT query: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// DeleteJob delete a job
+func (m *MySQL) DeleteJob(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM jobs WHERE uuid = ?`
+	if _, err := m.Conn.ExecContext(ctx, query, id.String()); err != nil {
+		return fmt.Errorf("failed to execute DELETE query: %w", err)
+	}
+
+	return nil
+}
@@ -12,8 +12,8 @@ import (
 
 // CreateTarget create a target
 func (m *MySQL) CreateTarget(ctx context.Context, target datastore.Target) error {
-	query := `INSERT INTO targets(uuid, scope, ghe_domain, github_personal_token, resource_type, runner_user) VALUES (?, ?, ?, ?, ?, ?)`
-	if _, err := m.Conn.ExecContext(ctx, query, target.UUID, target.Scope, target.GHEDomain, target.GitHubPersonalToken, target.ResourceType, target.RunnerUser); err != nil {
+	query := `INSERT INTO targets(uuid, scope, ghe_domain, github_personal_token, resource_type, runner_user, labels, job_retention) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	if _, err := m.Conn.ExecContext(ctx, query, target.UUID, target.Scope, target.GHEDomain, target.GitHubPersonalToken, target.ResourceType, target.RunnerUser, target.Labels, target.JobRetention); err != nil {
 		return fmt.Errorf("failed to execute INSERT query: %w", err)
 	}
 
@@ -23,7 +23,7 @@ func (m *MySQL) CreateTarget(ctx context.Context, target datastore.Target) error
 // GetTarget get a target
 func (m *MySQL) GetTarget(ctx context.Context, id uuid.UUID) (*datastore.Target, error) {
 	var t datastore.Target
-	query := fmt.Sprintf(`SELECT uuid, scope, ghe_domain, github_personal_token, resource_type, runner_user, created_at, updated_at FROM targets WHERE uuid = ?`)
+	query := fmt.Sprintf(`SELECT uuid, scope, ghe_domain, github_personal_token, resource_type, runner_user, labels, job_retention, created_at, updated_at FROM targets WHERE uuid = ?`)
 	if err := m.Conn.GetContext(ctx, &t, query, id.String()); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, datastore.ErrNotFound
@@ -38,7 +38,7 @@ func (m *MySQL) GetTarget(ctx context.Context, id uuid.UUID) (*datastore.Target,
 // GetTargetByScope get a target from scope
 func (m *MySQL) GetTargetByScope(ctx context.Context, gheDomain, scope string) (*datastore.Target, error) {
 	var t datastore.Target
-	query := fmt.Sprintf(`SELECT uuid, scope, ghe_domain, github_personal_token, resource_type, runner_user, created_at, updated_at FROM targets WHERE scope = "%s"`, scope)
+	query := fmt.Sprintf(`SELECT uuid, scope, ghe_domain, github_personal_token, resource_type, runner_user, labels, job_retention, created_at, updated_at FROM targets WHERE scope = "%s"`, scope)
 	if gheDomain != "" {
 		query = fmt.Sprintf(`%s AND ghe_domain = "%s"`, query, gheDomain)
 	}
@@ -53,6 +53,16 @@ func (m *MySQL) GetTargetByScope(ctx context.Context, gheDomain, scope string) (
 	return &t, nil
 }
 
+// UpdateTargetLabels update the labels of a target
+func (m *MySQL) UpdateTargetLabels(ctx context.Context, targetID uuid.UUID, labels datastore.TargetLabels) error {
+	query := `UPDATE targets SET labels = ? WHERE uuid = ?`
+	if _, err := m.Conn.ExecContext(ctx, query, labels, targetID.String()); err != nil {
+		return fmt.Errorf("failed to execute UPDATE query: %w", err)
+	}
+
+	return nil
+}
+
 // DeleteTarget delete a target
 func (m *MySQL) DeleteTarget(ctx context.Context, id uuid.UUID) error {
 	query := `DELETE FROM targets WHERE uuid = ?`
@@ -0,0 +1,139 @@
+package datastore
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/google/go-github/v47/github"
+	"github.com/whywaita/myshoes/pkg/gh"
+)
+
+// defaultPendingMinAge is the threshold used by AgeBasedPolicy when none is configured, matching
+// the age that was previously hardcoded in getPendingRunByRepo.
+const defaultPendingMinAge = 30 * time.Minute
+
+// PendingPolicy decides whether a queued/pending workflow run on a target has been waiting long
+// enough to be enqueued, and whether a target should be scanned for pending runs at all right
+// now. It is injected via config.Config.PendingPolicy so operators can pick a policy (or compose
+// several) without code changes.
+type PendingPolicy interface {
+	// ShouldScan reports whether pending detection should run at all for target right now.
+	// Returning false lets a policy back off, e.g. RateAwarePolicy during rate-limit pressure.
+	ShouldScan(ctx context.Context, target *Target) bool
+	// Threshold returns how long a queued/pending run on target must have been waiting before
+	// it is considered pending.
+	Threshold(target *Target) time.Duration
+}
+
+// AgeBasedPolicy is the default PendingPolicy: a run is pending once it has been queued for at
+// least MinAge, and every target is always scanned.
+type AgeBasedPolicy struct {
+	MinAge time.Duration
+}
+
+// ShouldScan always returns true; AgeBasedPolicy never skips a scan.
+func (p AgeBasedPolicy) ShouldScan(ctx context.Context, target *Target) bool {
+	return true
+}
+
+// Threshold returns MinAge, falling back to defaultPendingMinAge if it is unset.
+func (p AgeBasedPolicy) Threshold(target *Target) time.Duration {
+	if p.MinAge <= 0 {
+		return defaultPendingMinAge
+	}
+	return p.MinAge
+}
+
+// RateAwarePolicy wraps another PendingPolicy and skips scanning a target once its installation's
+// remaining GitHub API rate limit falls below MinRemainingFraction of its limit, so the pending
+// scraper automatically backs off during rate-limit pressure rather than amplifying it.
+type RateAwarePolicy struct {
+	Inner                PendingPolicy
+	MinRemainingFraction float64
+}
+
+// ShouldScan returns false if the installation behind target's scope is below the configured
+// remaining rate-limit fraction, otherwise it delegates to Inner.
+func (p RateAwarePolicy) ShouldScan(ctx context.Context, target *Target) bool {
+	remain, ok := gh.GetRateLimitRemain()[target.Scope]
+	if ok {
+		limit, ok := gh.GetRateLimitLimit()[target.Scope]
+		if ok && limit > 0 && float64(remain)/float64(limit) < p.MinRemainingFraction {
+			return false
+		}
+	}
+
+	return p.Inner.ShouldScan(ctx, target)
+}
+
+// Threshold delegates to Inner.
+func (p RateAwarePolicy) Threshold(target *Target) time.Duration {
+	return p.Inner.Threshold(target)
+}
+
+// PerRepoPolicy wraps Default with per-target threshold overrides, keyed by target UUID, so
+// operators can raise the threshold for chronically slow repos without affecting everyone else.
+type PerRepoPolicy struct {
+	Default   PendingPolicy
+	Overrides map[uuid.UUID]time.Duration
+}
+
+// ShouldScan delegates to Default.
+func (p PerRepoPolicy) ShouldScan(ctx context.Context, target *Target) bool {
+	return p.Default.ShouldScan(ctx, target)
+}
+
+// Threshold returns the override for target.UUID if one is configured, otherwise it delegates to
+// Default.
+func (p PerRepoPolicy) Threshold(target *Target) time.Duration {
+	if override, ok := p.Overrides[target.UUID]; ok {
+		return override
+	}
+	return p.Default.Threshold(target)
+}
+
+// isPendingRun reports whether run counts as pending under policy's threshold for target.
+func isPendingRun(policy PendingPolicy, target *Target, run *github.WorkflowRun) bool {
+	status := run.GetStatus()
+	if status != "queued" && status != "pending" {
+		return false
+	}
+
+	return time.Since(run.CreatedAt.Time) >= policy.Threshold(target)
+}
+
+// PendingScanStatus is the outcome of evaluating a PendingPolicy against a single target,
+// recorded so it can be surfaced as Prometheus metrics.
+type PendingScanStatus struct {
+	Threshold time.Duration
+	Skipped   bool
+}
+
+var (
+	pendingScanStatusMu sync.Mutex
+	pendingScanStatus   = map[uuid.UUID]PendingScanStatus{}
+)
+
+func recordPendingScanStatus(targetID uuid.UUID, status PendingScanStatus) {
+	pendingScanStatusMu.Lock()
+	defer pendingScanStatusMu.Unlock()
+
+	pendingScanStatus[targetID] = status
+}
+
+// GetPendingScanStatus returns a snapshot of the most recent PendingPolicy evaluation for every
+// target that has been scanned, keyed by target UUID. It is read by the metric package to export
+// the effective threshold and whether a scan was skipped.
+func GetPendingScanStatus() map[uuid.UUID]PendingScanStatus {
+	pendingScanStatusMu.Lock()
+	defer pendingScanStatusMu.Unlock()
+
+	snapshot := make(map[uuid.UUID]PendingScanStatus, len(pendingScanStatus))
+	for id, status := range pendingScanStatus {
+		snapshot[id] = status
+	}
+	return snapshot
+}
@@ -0,0 +1,55 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+func TestAgeBasedPolicy_Threshold(t *testing.T) {
+	target := &Target{UUID: uuid.NewV4()}
+
+	p := AgeBasedPolicy{MinAge: 10 * time.Minute}
+	if got := p.Threshold(target); got != 10*time.Minute {
+		t.Errorf("Threshold() = %s, want 10m", got)
+	}
+
+	zero := AgeBasedPolicy{}
+	if got := zero.Threshold(target); got != defaultPendingMinAge {
+		t.Errorf("Threshold() with zero MinAge = %s, want %s", got, defaultPendingMinAge)
+	}
+}
+
+func TestPerRepoPolicy_Threshold(t *testing.T) {
+	target := &Target{UUID: uuid.NewV4()}
+	other := &Target{UUID: uuid.NewV4()}
+
+	p := PerRepoPolicy{
+		Default:   AgeBasedPolicy{MinAge: 30 * time.Minute},
+		Overrides: map[uuid.UUID]time.Duration{target.UUID: 2 * time.Hour},
+	}
+
+	if got := p.Threshold(target); got != 2*time.Hour {
+		t.Errorf("Threshold() for overridden target = %s, want 2h", got)
+	}
+	if got := p.Threshold(other); got != 30*time.Minute {
+		t.Errorf("Threshold() for non-overridden target = %s, want 30m", got)
+	}
+}
+
+type alwaysScan struct{ threshold time.Duration }
+
+func (a alwaysScan) ShouldScan(ctx context.Context, target *Target) bool { return true }
+func (a alwaysScan) Threshold(target *Target) time.Duration              { return a.threshold }
+
+func TestRateAwarePolicy_ScansWhenNoRateLimitData(t *testing.T) {
+	target := &Target{UUID: uuid.NewV4(), Scope: "octocat/repo1"}
+
+	// No rate-limit data recorded for this scope yet: fall back to the inner policy.
+	p := RateAwarePolicy{Inner: alwaysScan{threshold: time.Minute}, MinRemainingFraction: 0.1}
+	if !p.ShouldScan(context.Background(), target) {
+		t.Errorf("ShouldScan() = false, want true when no rate-limit data is recorded")
+	}
+}
@@ -0,0 +1,44 @@
+package datastore
+
+import (
+	"context"
+	"time"
+
+	"github.com/whywaita/myshoes/pkg/logger"
+)
+
+// defaultRetentionSweepInterval is how often RunJobRetentionSweeper checks for
+// completed jobs whose retention period has elapsed.
+const defaultRetentionSweepInterval = 1 * time.Minute
+
+// RunJobRetentionSweeper periodically removes completed jobs whose retention period has
+// elapsed, so CompleteJob results don't grow the jobs table without bound. It is intended to
+// run as its own goroutine alongside the starter and runner loops, and returns only when ctx
+// is cancelled.
+func RunJobRetentionSweeper(ctx context.Context, ds Datastore) {
+	ticker := time.NewTicker(defaultRetentionSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sweepCompletedJobs(ctx, ds)
+		}
+	}
+}
+
+func sweepCompletedJobs(ctx context.Context, ds Datastore) {
+	expired, err := ds.ListCompletedJobsBefore(ctx, time.Now())
+	if err != nil {
+		logger.Logf(false, "failed to sweep completed jobs: failed to list completed jobs: %+v", err)
+		return
+	}
+
+	for _, job := range expired {
+		if err := ds.DeleteJob(ctx, job.UUID); err != nil {
+			logger.Logf(false, "failed to sweep completed jobs: failed to delete job (uuid: %s): %+v", job.UUID, err)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// fakeJobDatastore embeds Datastore so it only needs to implement the job-completion methods
+// exercised by sweepCompletedJobs.
+type fakeJobDatastore struct {
+	Datastore
+	completed []Job
+	deleted   []uuid.UUID
+}
+
+func (f *fakeJobDatastore) ListCompletedJobsBefore(ctx context.Context, now time.Time) ([]Job, error) {
+	var expired []Job
+	for _, j := range f.completed {
+		if j.CompletedAt.Add(j.Retention).Before(now) {
+			expired = append(expired, j)
+		}
+	}
+	return expired, nil
+}
+
+func (f *fakeJobDatastore) DeleteJob(ctx context.Context, id uuid.UUID) error {
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+func TestSweepCompletedJobs_DeletesOnlyExpired(t *testing.T) {
+	expired := Job{UUID: uuid.NewV4(), CompletedAt: time.Now().Add(-time.Hour), Retention: time.Minute}
+	notYetExpired := Job{UUID: uuid.NewV4(), CompletedAt: time.Now(), Retention: time.Hour}
+
+	ds := &fakeJobDatastore{completed: []Job{expired, notYetExpired}}
+
+	sweepCompletedJobs(context.Background(), ds)
+
+	if len(ds.deleted) != 1 {
+		t.Fatalf("want 1 job deleted, got %d", len(ds.deleted))
+	}
+	if !uuid.Equal(ds.deleted[0], expired.UUID) {
+		t.Errorf("deleted job = %s, want %s", ds.deleted[0], expired.UUID)
+	}
+}
+
+func TestRunJobRetentionSweeper_ReturnsOnContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		RunJobRetentionSweeper(ctx, &fakeJobDatastore{})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("RunJobRetentionSweeper did not return after context cancellation")
+	}
+}
@@ -0,0 +1,61 @@
+package datastore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/whywaita/myshoes/pkg/gh"
+)
+
+// SearchRepo resolves the Target that should run jobs for repo (an "owner/repo" full name),
+// preferring a target registered for the repository itself and falling back to one registered
+// for the owning organization. It is a thin wrapper around SearchRepoWithLabels with no label
+// requirement.
+func SearchRepo(ctx context.Context, ds Datastore, repo string) (*Target, error) {
+	return SearchRepoWithLabels(ctx, ds, repo, nil)
+}
+
+// SearchRepoWithLabels resolves the Target that should run a job for repo (an "owner/repo" full
+// name) whose advertised labels are a superset of requiredLabels, e.g. the labels parsed from a
+// workflow's `runs-on` via ParseRunsOnLabels. Like SearchRepo, it prefers a target registered for
+// the repository itself over one registered for the owning organization: the repository's
+// target is returned if its labels satisfy requiredLabels, otherwise the organization's. Each
+// scope contributes only the single target GetTargetByScope returns for it, so several targets
+// registered under the same scope are not chosen between by their labels.
+func SearchRepoWithLabels(ctx context.Context, ds Datastore, repo string, requiredLabels TargetLabels) (*Target, error) {
+	owner, _ := gh.DivideScope(repo)
+
+	candidates, err := findCandidateTargets(ctx, ds, repo, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(requiredLabels) == 0 {
+		return &candidates[0], nil
+	}
+
+	return SelectTargetByLabels(candidates, requiredLabels)
+}
+
+// findCandidateTargets looks up a Target for each scope in order, returning every one found. An
+// unregistered scope is skipped rather than treated as an error; only if none of the scopes
+// resolve to a Target is ErrNotFound returned.
+func findCandidateTargets(ctx context.Context, ds Datastore, scopes ...string) ([]Target, error) {
+	var candidates []Target
+	for _, scope := range scopes {
+		target, err := ds.GetTargetByScope(ctx, "", scope)
+		if err != nil {
+			if err == ErrNotFound {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get target by scope (scope: %s): %w", scope, err)
+		}
+		candidates = append(candidates, *target)
+	}
+
+	if len(candidates) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return candidates, nil
+}
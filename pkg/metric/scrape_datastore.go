@@ -0,0 +1,200 @@
+package metric
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/whywaita/myshoes/pkg/datastore"
+)
+
+const datastoreName = "datastore"
+
+// datastoreSnapshotTTL bounds how often ScraperDatastore re-reads ds.ListJobs / ds.ListRunners.
+// Prometheus may be scraped much more often than the underlying tables change, so a short TTL
+// avoids hammering the database on every pull.
+const datastoreSnapshotTTL = 10 * time.Second
+
+// defaultPendingOverThresholdAge is used for a job whose target has not had a PendingPolicy
+// evaluation recorded yet (see datastore.GetPendingScanStatus).
+const defaultPendingOverThresholdAge = 30 * time.Minute
+
+const pendingOverThresholdStatus = "pending_over_threshold"
+
+var (
+	datastoreJobsTotal = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, datastoreName, "jobs_total"),
+		"The number of jobs grouped by status",
+		[]string{"status"}, nil,
+	)
+	datastoreRunnersTotal = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, datastoreName, "runners_total"),
+		"The number of runners grouped by status, resource_type and target_id",
+		[]string{"status", "resource_type", "target_id"}, nil,
+	)
+	datastoreJobWaitSeconds = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "job_wait_seconds"),
+		"Time elapsed between a job being created and a runner being assigned to it",
+		nil, nil,
+	)
+	datastoreJobResultBytes = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, datastoreName, "job_result_bytes_total"),
+		"The total size in bytes of completed job results not yet removed by the retention sweeper",
+		nil, nil,
+	)
+	targetLabelsInfo = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "target", "labels"),
+		"Label key/value advertised by a target, always 1",
+		[]string{"target_id", "label", "value"}, nil,
+	)
+)
+
+// targetLabel is a single (target, key, value) label triple, flattened out of
+// datastore.Target.Labels for export as an info metric.
+type targetLabel struct {
+	targetID string
+	key      string
+	value    string
+}
+
+// datastoreSnapshot is the cached result of reading ds.ListJobs / ds.ListCompletedJobs / ds.ListRunners.
+type datastoreSnapshot struct {
+	jobsByStatus    map[string]int
+	runnersByLabels map[[3]string]int
+	jobResultBytes  int
+	targetLabels    []targetLabel
+}
+
+// ScraperDatastore is scraper implement for job/runner counts in the datastore
+type ScraperDatastore struct {
+	mu        sync.Mutex
+	updatedAt time.Time
+	snapshot  datastoreSnapshot
+}
+
+// Name return name
+func (s *ScraperDatastore) Name() string {
+	return datastoreName
+}
+
+// Help return help
+func (s *ScraperDatastore) Help() string {
+	return "Collect job and runner counts from datastore"
+}
+
+// Scrape scrape metrics
+func (s *ScraperDatastore) Scrape(ctx context.Context, ds datastore.Datastore, ch chan<- prometheus.Metric) error {
+	snapshot, err := s.getSnapshot(ctx, ds)
+	if err != nil {
+		return fmt.Errorf("failed to get datastore snapshot: %w", err)
+	}
+
+	for status, count := range snapshot.jobsByStatus {
+		ch <- prometheus.MustNewConstMetric(
+			datastoreJobsTotal, prometheus.GaugeValue, float64(count), status)
+	}
+	for labels, count := range snapshot.runnersByLabels {
+		ch <- prometheus.MustNewConstMetric(
+			datastoreRunnersTotal, prometheus.GaugeValue, float64(count), labels[0], labels[1], labels[2])
+	}
+	waitBuckets, waitSum, waitCount := datastore.GetJobWaitHistogram()
+	ch <- prometheus.MustNewConstHistogram(
+		datastoreJobWaitSeconds, waitCount, waitSum, waitBuckets)
+
+	ch <- prometheus.MustNewConstMetric(
+		datastoreJobResultBytes, prometheus.GaugeValue, float64(snapshot.jobResultBytes))
+
+	for _, l := range snapshot.targetLabels {
+		ch <- prometheus.MustNewConstMetric(
+			targetLabelsInfo, prometheus.GaugeValue, 1, l.targetID, l.key, l.value)
+	}
+
+	return nil
+}
+
+func (s *ScraperDatastore) getSnapshot(ctx context.Context, ds datastore.Datastore) (datastoreSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if time.Since(s.updatedAt) < datastoreSnapshotTTL {
+		return s.snapshot, nil
+	}
+
+	snapshot, err := buildDatastoreSnapshot(ctx, ds)
+	if err != nil {
+		return datastoreSnapshot{}, err
+	}
+
+	s.snapshot = snapshot
+	s.updatedAt = time.Now()
+	return s.snapshot, nil
+}
+
+func buildDatastoreSnapshot(ctx context.Context, ds datastore.Datastore) (datastoreSnapshot, error) {
+	jobs, err := ds.ListJobs(ctx)
+	if err != nil {
+		return datastoreSnapshot{}, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	completedJobs, err := ds.ListCompletedJobs(ctx)
+	if err != nil {
+		return datastoreSnapshot{}, fmt.Errorf("failed to list completed jobs: %w", err)
+	}
+	runners, err := ds.ListRunners(ctx)
+	if err != nil {
+		return datastoreSnapshot{}, fmt.Errorf("failed to list runners: %w", err)
+	}
+	targets, err := ds.ListTargets(ctx)
+	if err != nil {
+		return datastoreSnapshot{}, fmt.Errorf("failed to list targets: %w", err)
+	}
+
+	pendingScanStatus := datastore.GetPendingScanStatus()
+
+	jobsByStatus := map[string]int{}
+	now := time.Now()
+	for _, j := range jobs {
+		jobsByStatus["queued"]++
+
+		threshold := defaultPendingOverThresholdAge
+		if status, ok := pendingScanStatus[j.TargetID]; ok {
+			threshold = status.Threshold
+		}
+		if now.Sub(j.CreatedAt) >= threshold {
+			jobsByStatus[pendingOverThresholdStatus]++
+		}
+	}
+
+	var jobResultBytes int
+	for _, j := range completedJobs {
+		jobsByStatus[string(j.ResultStatus)]++
+		jobResultBytes += len(j.Result)
+	}
+
+	runnersByLabels := map[[3]string]int{}
+	for _, r := range runners {
+		key := [3]string{string(r.Status), string(r.ResourceType), r.TargetID.String()}
+		runnersByLabels[key]++
+	}
+
+	var targetLabels []targetLabel
+	for _, t := range targets {
+		for key, value := range t.Labels {
+			targetLabels = append(targetLabels, targetLabel{
+				targetID: t.UUID.String(),
+				key:      key,
+				value:    value,
+			})
+		}
+	}
+
+	return datastoreSnapshot{
+		jobsByStatus:    jobsByStatus,
+		runnersByLabels: runnersByLabels,
+		jobResultBytes:  jobResultBytes,
+		targetLabels:    targetLabels,
+	}, nil
+}
+
+var _ Scraper = &ScraperDatastore{}
@@ -0,0 +1,55 @@
+package metric
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/whywaita/myshoes/pkg/datastore"
+)
+
+const pendingName = "pending_scan"
+
+var (
+	pendingScanThresholdSeconds = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, pendingName, "threshold_seconds"),
+		"The effective PendingPolicy threshold for a target's most recent pending run scan",
+		[]string{"target_id"}, nil,
+	)
+	pendingScanSkipped = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, pendingName, "skipped"),
+		"Whether the most recent pending run scan for a target was skipped by its PendingPolicy (1 = skipped)",
+		[]string{"target_id"}, nil,
+	)
+)
+
+// ScraperPending is scraper implement for PendingPolicy outcomes
+type ScraperPending struct{}
+
+// Name return name
+func (ScraperPending) Name() string {
+	return pendingName
+}
+
+// Help return help
+func (ScraperPending) Help() string {
+	return "Collect PendingPolicy threshold and skip decisions"
+}
+
+// Scrape scrape metrics
+func (ScraperPending) Scrape(ctx context.Context, ds datastore.Datastore, ch chan<- prometheus.Metric) error {
+	for targetID, status := range datastore.GetPendingScanStatus() {
+		ch <- prometheus.MustNewConstMetric(
+			pendingScanThresholdSeconds, prometheus.GaugeValue, status.Threshold.Seconds(), targetID.String())
+
+		skipped := 0.0
+		if status.Skipped {
+			skipped = 1.0
+		}
+		ch <- prometheus.MustNewConstMetric(
+			pendingScanSkipped, prometheus.GaugeValue, skipped, targetID.String())
+	}
+
+	return nil
+}
+
+var _ Scraper = ScraperPending{}
@@ -0,0 +1,48 @@
+// Package runner manages the lifecycle of runner instances: registering them against a
+// target, deleting them once a job finishes, and recording what happened to the job that
+// ran on them.
+package runner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/pkg/datastore"
+)
+
+// ConcurrencyDeleting is the number of runner deletions currently in flight. It is read by
+// pkg/metric to export myshoes_memory_runner_queue_concurrency_deleting.
+var ConcurrencyDeleting int64
+
+// AssignJob registers runner against job's target and records how long job waited in the queue
+// before this assignment happened, via datastore.RecordJobAssigned. This is the one place a
+// job's wait time is observed, so myshoes_job_wait_seconds stays a proper monotonically
+// increasing histogram instead of resampling queue age on every scrape.
+func AssignJob(ctx context.Context, ds datastore.Datastore, job *datastore.Job, r datastore.Runner) error {
+	if err := ds.CreateRunner(ctx, r); err != nil {
+		return fmt.Errorf("failed to create runner (uuid: %s): %w", r.UUID, err)
+	}
+
+	datastore.RecordJobAssigned(time.Since(job.CreatedAt))
+
+	return nil
+}
+
+// CompleteJobForRunner records the result of the job that ran on runner r and deletes the
+// runner, since a runner is single-use. Unlike deleting the job outright, CompleteJob keeps
+// the job's result around for its retention period so operators can inspect what happened
+// after the fact; see datastore.RunJobRetentionSweeper for the eventual cleanup.
+func CompleteJobForRunner(ctx context.Context, ds datastore.Datastore, r *datastore.Runner, jobID uuid.UUID, result []byte, status datastore.JobResultStatus) error {
+	if err := ds.CompleteJob(ctx, jobID, result, status); err != nil {
+		return fmt.Errorf("failed to complete job (uuid: %s): %w", jobID, err)
+	}
+
+	if err := ds.DeleteRunner(ctx, r.UUID, time.Now(), datastore.RunnerStatusCompleted); err != nil {
+		return fmt.Errorf("failed to delete runner (uuid: %s): %w", r.UUID, err)
+	}
+
+	return nil
+}
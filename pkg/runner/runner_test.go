@@ -0,0 +1,49 @@
+package runner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/datastore/memory"
+)
+
+func TestCompleteJobForRunner(t *testing.T) {
+	ds, err := memory.New()
+	if err != nil {
+		t.Fatalf("failed to create memory datastore: %+v", err)
+	}
+	ctx := context.Background()
+
+	job := datastore.Job{UUID: uuid.NewV4(), CreatedAt: time.Now(), Retention: time.Hour}
+	if err := ds.EnqueueJob(ctx, job); err != nil {
+		t.Fatalf("failed to enqueue job: %+v", err)
+	}
+
+	r := datastore.Runner{UUID: uuid.NewV4(), CreatedAt: time.Now()}
+	if err := ds.CreateRunner(ctx, r); err != nil {
+		t.Fatalf("failed to create runner: %+v", err)
+	}
+
+	if err := CompleteJobForRunner(ctx, ds, &r, job.UUID, []byte("done"), datastore.JobResultStatusSuccess); err != nil {
+		t.Fatalf("CompleteJobForRunner returned error: %+v", err)
+	}
+
+	got, err := ds.GetJob(ctx, job.UUID)
+	if err != nil {
+		t.Fatalf("failed to get job: %+v", err)
+	}
+	if got.ResultStatus != datastore.JobResultStatusSuccess {
+		t.Errorf("ResultStatus = %s, want %s", got.ResultStatus, datastore.JobResultStatusSuccess)
+	}
+	if got.CompletedAt.IsZero() {
+		t.Error("want CompletedAt to be set")
+	}
+
+	if _, err := ds.GetRunner(ctx, r.UUID); err != datastore.ErrNotFound {
+		t.Errorf("GetRunner after CompleteJobForRunner = %v, want %v", err, datastore.ErrNotFound)
+	}
+}
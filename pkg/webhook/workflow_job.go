@@ -0,0 +1,59 @@
+// Package webhook turns incoming GitHub webhook events into datastore writes.
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/v47/github"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/logger"
+	"github.com/whywaita/myshoes/pkg/runner"
+)
+
+// NewWorkflowJobHandler returns an http.HandlerFunc that decodes a `workflow_job` webhook
+// payload, enqueues the job it describes (resolving its target and persisting its
+// RequiredLabels via datastore.NewJobFromWorkflowJobEvent), and assigns it a runner via
+// runner.AssignJob. The caller is expected to have already routed the request here based on
+// the `X-GitHub-Event: workflow_job` header.
+func NewWorkflowJobHandler(ds datastore.Datastore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var event github.WorkflowJobEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			http.Error(w, "invalid workflow_job payload", http.StatusBadRequest)
+			return
+		}
+
+		job, err := datastore.NewJobFromWorkflowJobEvent(r.Context(), ds, &event)
+		if err != nil {
+			logger.Logf(false, "failed to build job from workflow_job event: %+v", err)
+			http.Error(w, "failed to resolve target for job", http.StatusUnprocessableEntity)
+			return
+		}
+
+		if err := ds.EnqueueJob(r.Context(), *job); err != nil {
+			logger.Logf(false, "failed to enqueue job (uuid: %s): %+v", job.UUID, err)
+			http.Error(w, "failed to enqueue job", http.StatusInternalServerError)
+			return
+		}
+
+		newRunner := datastore.Runner{
+			UUID:          uuid.NewV4(),
+			TargetID:      job.TargetID,
+			RepositoryURL: fmt.Sprintf("https://github.com/%s", job.Repository),
+			Status:        datastore.RunnerStatusInitialize,
+			CreatedAt:     time.Now(),
+		}
+		if err := runner.AssignJob(r.Context(), ds, job, newRunner); err != nil {
+			logger.Logf(false, "failed to assign runner to job (uuid: %s): %+v", job.UUID, err)
+			http.Error(w, "failed to assign runner", http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
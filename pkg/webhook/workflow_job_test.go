@@ -0,0 +1,92 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-github/v47/github"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/datastore/memory"
+)
+
+func TestNewWorkflowJobHandler_EnqueuesJobAndAssignsRunner(t *testing.T) {
+	ds, err := memory.New()
+	if err != nil {
+		t.Fatalf("failed to create memory datastore: %+v", err)
+	}
+
+	target := datastore.Target{
+		UUID:   uuid.NewV4(),
+		Scope:  "octocat/repo1",
+		Labels: datastore.TargetLabels{"self-hosted": "true", "arch": "amd64"},
+	}
+	if err := ds.CreateTarget(context.Background(), target); err != nil {
+		t.Fatalf("failed to create target: %+v", err)
+	}
+
+	event := &github.WorkflowJobEvent{
+		Repo: &github.Repository{FullName: github.String("octocat/repo1")},
+		WorkflowJob: &github.WorkflowJob{
+			Labels: []string{"self-hosted", "arch=amd64"},
+		},
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("failed to marshal event: %+v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook/workflow_job", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	_, _, waitCountBefore := datastore.GetJobWaitHistogram()
+
+	NewWorkflowJobHandler(ds).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("want status %d, got %d (body: %s)", http.StatusAccepted, rec.Code, rec.Body.String())
+	}
+
+	jobs, err := ds.ListJobs(context.Background())
+	if err != nil {
+		t.Fatalf("failed to list jobs: %+v", err)
+	}
+	if len(jobs) != 1 {
+		t.Fatalf("want 1 enqueued job, got %d", len(jobs))
+	}
+
+	got := jobs[0].RequiredLabels
+	want := datastore.TargetLabels{"self-hosted": "true", "arch": "amd64"}
+	if len(got) != len(want) {
+		t.Fatalf("RequiredLabels = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("RequiredLabels[%q] = %q, want %q", k, got[k], v)
+		}
+	}
+	if jobs[0].TargetID != target.UUID {
+		t.Errorf("TargetID = %s, want %s", jobs[0].TargetID, target.UUID)
+	}
+
+	runners, err := ds.ListRunners(context.Background())
+	if err != nil {
+		t.Fatalf("failed to list runners: %+v", err)
+	}
+	if len(runners) != 1 {
+		t.Fatalf("want 1 assigned runner, got %d", len(runners))
+	}
+	if runners[0].TargetID != target.UUID {
+		t.Errorf("runner TargetID = %s, want %s", runners[0].TargetID, target.UUID)
+	}
+
+	_, _, waitCountAfter := datastore.GetJobWaitHistogram()
+	if waitCountAfter != waitCountBefore+1 {
+		t.Errorf("want job wait histogram count to increase by 1 from real assignment, got %d -> %d", waitCountBefore, waitCountAfter)
+	}
+}